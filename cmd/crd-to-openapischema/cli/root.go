@@ -6,8 +6,10 @@ import (
 	"strings"
 
 	"github.com/arakos/crd-to-openapischema/pkg/generator"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 func RootCmd() *cobra.Command {
@@ -23,14 +25,60 @@ func RootCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			v := viper.GetViper()
 
-			_, err := generator.Generate(args[0], v.GetString("output-dir"))
-			return err
+			if v.GetBool("from-cluster") {
+				if len(args) > 0 {
+					return fmt.Errorf("--from-cluster does not take positional source arguments, got: %v", args)
+				}
+				if v.GetBool("kubeconform-tree") {
+					return fmt.Errorf("--kubeconform-tree is not supported together with --from-cluster")
+				}
+
+				selector := labels.Everything()
+				if sel := v.GetString("selector"); sel != "" {
+					parsed, err := labels.Parse(sel)
+					if err != nil {
+						return errors.Wrap(err, "failed to parse --selector")
+					}
+					selector = parsed
+				}
+
+				_, err := generator.GenerateFromCluster(v.GetString("kubeconfig"), v.GetStringSlice("context"), selector, v.GetString("group-prefix"), v.GetString("go-source"), v.GetString("format"), v.GetString("output-dir"))
+				return err
+			}
+
+			var schemas map[string]string
+			var genErr error
+			if v.GetBool("kubeconform-tree") {
+				schemas, genErr = generator.GenerateKubeconformTree(args, v.GetString("go-source"), v.GetString("format"), v.GetString("kubernetes-version"), v.GetBool("strict"))
+			} else {
+				schemas, genErr = generator.GenerateAll(args, v.GetString("go-source"), v.GetString("format"))
+			}
+
+			_, writeErr := generator.WriteSchemas(schemas, v.GetString("output-dir"))
+			switch {
+			case genErr != nil && writeErr != nil:
+				return fmt.Errorf("%v\n%v", genErr, writeErr)
+			case genErr != nil:
+				return genErr
+			default:
+				return writeErr
+			}
 		},
 	}
 
 	cobra.OnInitialize(initConfig)
 
 	cmd.Flags().String("output-dir", "./", "directory to save the schemas in")
+	cmd.Flags().Bool("from-cluster", false, "fetch CustomResourceDefinitions directly from a live cluster instead of a file or URL")
+	cmd.Flags().String("kubeconfig", "", "path to the kubeconfig file to use with --from-cluster (defaults to the standard kubeconfig loading rules)")
+	cmd.Flags().StringSlice("context", nil, "kubeconfig context to read CRDs from with --from-cluster; may be repeated to snapshot multiple clusters")
+	cmd.Flags().String("selector", "", "label selector to filter CustomResourceDefinitions by with --from-cluster")
+	cmd.Flags().String("group-prefix", "", "only include CustomResourceDefinitions whose group has this prefix with --from-cluster")
+	cmd.Flags().String("go-source", "", "directory of Go type declarations to merge kubebuilder validation markers from")
+	cmd.Flags().String("format", "json-pretty", "output format for the generated schemas: json, json-pretty, or yaml")
+	cmd.Flags().Bool("kubeconform-tree", false, "lay schemas out as the kubeconform -schema-location directory tree, with an index.json")
+	cmd.Flags().Bool("strict", false, "with --kubeconform-tree, also emit a -strict variant of every schema with additionalProperties: false injected at every object node")
+	cmd.Flags().String("kubernetes-version", "master", "Kubernetes version segment to use in the --kubeconform-tree directory layout")
 
 	viper.BindPFlags(cmd.Flags())
 
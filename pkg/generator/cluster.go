@@ -0,0 +1,109 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// GenerateFromCluster lists CustomResourceDefinitions directly from one or
+// more kubeconfig contexts, narrowed by selector and/or groupPrefix, and
+// writes their schemas to outputDir. Output is namespaced per context
+// (<context>/<kind>-<group>-<version>.json) so a single invocation can
+// snapshot schemas from several clusters, e.g. prod/staging/dev, at once.
+func GenerateFromCluster(kubeconfig string, contexts []string, selector labels.Selector, groupPrefix string, goSourceDir string, format string, outputDir string) (files []string, err error) {
+	ser, err := NewSerializer(format)
+	if err != nil {
+		return files, err
+	}
+
+	var goTypes map[string]map[string]*goFieldMarkers
+	if goSourceDir != "" {
+		goTypes, err = parseGoSourceMarkers(goSourceDir)
+		if err != nil {
+			return files, errors.Wrap(err, "failed to parse go source markers")
+		}
+	}
+
+	if len(contexts) == 0 {
+		contexts = []string{""}
+	}
+
+	msg := ""
+	for _, kubeContext := range contexts {
+		contextFiles, err := generateFromClusterContext(kubeconfig, kubeContext, selector, groupPrefix, goTypes, ser, outputDir)
+		if err != nil {
+			msg += fmt.Sprintf("\t%v\n", err)
+			continue
+		}
+		files = append(files, contextFiles...)
+	}
+
+	if msg != "" {
+		return files, fmt.Errorf("Failed to generate schemas for following contexts:\n%v", msg)
+	}
+	return files, nil
+}
+
+func generateFromClusterContext(kubeconfig, kubeContext string, selector labels.Selector, groupPrefix string, goTypes map[string]map[string]*goFieldMarkers, ser Serializer, outputDir string) (files []string, err error) {
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+	).ClientConfig()
+	if err != nil {
+		return files, errors.Wrap(err, "failed to load kubeconfig")
+	}
+
+	clientset, err := apiextensionsclientset.NewForConfig(config)
+	if err != nil {
+		return files, errors.Wrap(err, "failed to create apiextensions client")
+	}
+
+	listOpts := metav1.ListOptions{}
+	if selector != nil {
+		listOpts.LabelSelector = selector.String()
+	}
+
+	crds, err := clientset.ApiextensionsV1().CustomResourceDefinitions().List(context.Background(), listOpts)
+	if err != nil {
+		return files, errors.Wrap(err, "failed to list CustomResourceDefinitions")
+	}
+
+	contextDir := outputDir
+	if kubeContext != "" {
+		contextDir = filepath.Join(outputDir, kubeContext)
+	}
+
+	msg := ""
+	for i := range crds.Items {
+		crd := crds.Items[i]
+		if groupPrefix != "" && !strings.HasPrefix(crd.Spec.Group, groupPrefix) {
+			continue
+		}
+		schemas, err := generateSchemaFromCRD(&crd, goTypes, ser)
+		if err != nil {
+			msg += fmt.Sprintf("\t%v: %v\n", crd.Name, err)
+			continue
+		}
+		for name, schema := range schemas {
+			outfile := filepath.Join(contextDir, name)
+			if err := writeFile([]byte(schema), outfile); err != nil {
+				msg += fmt.Sprintf("\t%v: %v\n", crd.Name, err)
+				continue
+			}
+			files = append(files, outfile)
+		}
+	}
+
+	if msg != "" {
+		return files, fmt.Errorf("Failed to write following files:\n%v", msg)
+	}
+	return files, nil
+}
@@ -2,7 +2,6 @@ package generator
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -18,13 +17,102 @@ import (
 	extensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	extensionsscheme "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/scheme"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/serializer"
+	k8sserializer "k8s.io/apimachinery/pkg/runtime/serializer"
 )
 
-const generatedCrdsPattern = "{{ .ResourceKind }}{{ .KindSuffix }}.json"
+const generatedCrdsPattern = "{{ .ResourceKind }}{{ .KindSuffix }}.{{ .Ext }}"
+
+// Generate reads the CRD(s) at customResourceDefinitionPath and writes their
+// OpenAPIv3 schemas to outputDir, serialized per format (see NewSerializer).
+// If goSourceDir is non-empty, kubebuilder validation markers found in the Go
+// source under that directory are grafted onto the matching schema
+// properties before they're written out.
+func Generate(customResourceDefinitionPath string, outputDir string, goSourceDir string, format string) (files []string, err error) {
+	schemas, genErr := GenerateAll([]string{customResourceDefinitionPath}, goSourceDir, format)
+	files, writeErr := WriteSchemas(schemas, outputDir)
+	switch {
+	case genErr != nil && writeErr != nil:
+		return files, fmt.Errorf("%v\n%v", genErr, writeErr)
+	case genErr != nil:
+		return files, genErr
+	default:
+		return files, writeErr
+	}
+}
+
+// GenerateString is a convenience wrapper around GenerateAll for a single
+// source path.
+func GenerateString(customResourceDefinitionPath string, goSourceDir string, format string) (schemas map[string]string, err error) {
+	return GenerateAll([]string{customResourceDefinitionPath}, goSourceDir, format)
+}
 
-func Generate(customResourceDefinitionPath string, outputDir string) (files []string, err error) {
+// GenerateAll expands paths - directories, glob patterns, and individual
+// files/URLs/oci:// and helm:// sources alike - and generates a schema for
+// every CRD version found across all of them, including every document of
+// any multi-document YAML streams encountered along the way.
+func GenerateAll(paths []string, goSourceDir string, format string) (schemas map[string]string, err error) {
+	ser, err := NewSerializer(format)
+	if err != nil {
+		return schemas, err
+	}
 
+	expanded, err := expandPaths(paths)
+	if err != nil {
+		return schemas, err
+	}
+
+	var goTypes map[string]map[string]*goFieldMarkers
+	if goSourceDir != "" {
+		goTypes, err = parseGoSourceMarkers(goSourceDir)
+		if err != nil {
+			return schemas, errors.Wrap(err, "failed to parse go source markers")
+		}
+	}
+
+	schemas = make(map[string]string)
+	msg := ""
+	for _, p := range expanded {
+		crdBlobs, err := readCRDFromPath(p)
+		if err != nil {
+			msg += fmt.Sprintf("\t%v: %v\n", p, err)
+			continue
+		}
+
+		for _, blob := range crdBlobs {
+			docs, err := splitYAMLDocuments(blob)
+			if err != nil {
+				msg += fmt.Sprintf("\t%v: %v\n", p, err)
+				continue
+			}
+
+			for _, doc := range docs {
+				crdObj, err := decodeCRD(doc)
+				if err != nil {
+					msg += fmt.Sprintf("\t%v: %v\n", p, err)
+					continue
+				}
+
+				docSchemas, err := generateSchemaFromCRD(crdObj, goTypes, ser)
+				if err != nil {
+					msg += fmt.Sprintf("\t%v: %v\n", p, err)
+					continue
+				}
+				for name, schema := range docSchemas {
+					schemas[name] = schema
+				}
+			}
+		}
+	}
+
+	if msg != "" {
+		return schemas, fmt.Errorf("Failed to generate schema for following sources:\n%v", msg)
+	}
+	return schemas, nil
+}
+
+// WriteSchemas writes a name->contents map of schemas, as produced by
+// GenerateAll, underneath outputDir.
+func WriteSchemas(schemas map[string]string, outputDir string) (files []string, err error) {
 	if !filepath.IsAbs(outputDir) {
 		outputDir, err = filepath.Abs(outputDir)
 		if err != nil {
@@ -36,11 +124,6 @@ func Generate(customResourceDefinitionPath string, outputDir string) (files []st
 		}
 	}
 
-	schemas, err := GenerateString(customResourceDefinitionPath)
-	if err != nil {
-		return files, err
-	}
-
 	msg := ""
 	for name, schema := range schemas {
 		outfile := filepath.Join(outputDir, name)
@@ -63,49 +146,37 @@ func Generate(customResourceDefinitionPath string, outputDir string) (files []st
 	return files, nil
 }
 
-func GenerateString(customResourceDefinitionPath string) (schemas map[string]string, err error) {
-	msg := fmt.Sprintf("error on crd '%v'", customResourceDefinitionPath)
-	crdContents, err := readCRDFromPath(customResourceDefinitionPath)
-	if err != nil {
-		return schemas, errors.Wrap(err, msg)
-	}
-
-	crdObj, err := decodeCRD(crdContents)
-	if err != nil {
-		return schemas, errors.Wrap(err, msg)
-	}
-
-	schemas, err = generateSchemaFromCRD(crdObj)
-	if err != nil {
-		return schemas, errors.Wrap(err, msg)
-	}
-	return schemas, nil
-}
-
 func decodeCRD(raw []byte) (interface{}, error) {
 	scheme := runtime.NewScheme()
 	extensionsscheme.AddToScheme(scheme)
-	crd, _, err := serializer.NewCodecFactory(scheme).UniversalDeserializer().Decode(raw, nil, nil)
+	crd, _, err := k8sserializer.NewCodecFactory(scheme).UniversalDeserializer().Decode(raw, nil, nil)
 	return crd, err
 }
 
-func generateSchemaFromCRD(crdObj interface{}) (res map[string]string, err error) {
+func generateSchemaFromCRD(crdObj interface{}, goTypes map[string]map[string]*goFieldMarkers, ser Serializer) (res map[string]string, err error) {
 	schemas := make(map[string]interface{})
 	switch v := crdObj.(type) {
 	case *extensionsv1.CustomResourceDefinition:
 		crd := crdObj.(*extensionsv1.CustomResourceDefinition)
 		for _, version := range crd.Spec.Versions {
-			name, err := generateFilename(generatedCrdsPattern, crd.Spec.Names.Kind, fmt.Sprintf("%v/%v", crd.Spec.Group, version.Name))
+			name, err := generateFilename(generatedCrdsPattern, crd.Spec.Names.Kind, fmt.Sprintf("%v/%v", crd.Spec.Group, version.Name), ser.Ext(), "", false)
 			if err != nil {
 				return res, err
 			}
 			if version.Schema != nil {
-				schemas[name] = *version.Schema.OpenAPIV3Schema
+				schema := *version.Schema.OpenAPIV3Schema
+				if goTypes != nil {
+					mergeKubebuilderMarkers(&schema, crd.Spec.Names.Kind, goTypes)
+				}
+				schemas[name] = schema
 			}
 		}
 	case *extensionsv1beta1.CustomResourceDefinition:
+		if goTypes != nil {
+			return res, fmt.Errorf("--go-source is only supported for v1 CustomResourceDefinitions")
+		}
 		crd := crdObj.(*extensionsv1beta1.CustomResourceDefinition)
-		name, err := generateFilename(generatedCrdsPattern, crd.Spec.Names.Kind, fmt.Sprintf("%v/%v", crd.Spec.Group, crd.Spec.Version))
+		name, err := generateFilename(generatedCrdsPattern, crd.Spec.Names.Kind, fmt.Sprintf("%v/%v", crd.Spec.Group, crd.Spec.Version), ser.Ext(), "", false)
 		if err != nil {
 			return res, err
 		}
@@ -123,7 +194,7 @@ func generateSchemaFromCRD(crdObj interface{}) (res map[string]string, err error
 	msg := ""
 	res = make(map[string]string)
 	for name, schema := range schemas {
-		b, err := json.MarshalIndent(schema, "", "  ")
+		b, err := ser.Serialize(schema)
 		if err != nil {
 			msg += fmt.Sprintf("Failed to marschal schema: %v\n", err)
 		} else {
@@ -138,7 +209,7 @@ func generateSchemaFromCRD(crdObj interface{}) (res map[string]string, err error
 
 // copied from github.com/yannh/kubeconform/pkg/registry.schemaPath method because filenaming is important for validation schema
 // with yannhs kubeconform validator
-func generateFilename(tpl, resourceKind, resourceAPIVersion string) (string, error) {
+func generateFilename(tpl, resourceKind, resourceAPIVersion, ext, normalizedKubernetesVersion string, strict bool) (string, error) {
 	groupParts := strings.Split(resourceAPIVersion, "/")
 	versionParts := strings.Split(groupParts[0], ".")
 
@@ -147,21 +218,32 @@ func generateFilename(tpl, resourceKind, resourceAPIVersion string) (string, err
 		kindSuffix += "-" + strings.ToLower(groupParts[1])
 	}
 
+	strictSuffix := ""
+	if strict {
+		strictSuffix = "-strict"
+	}
+
 	tmpl, err := template.New("tpl").Parse(tpl)
 	if err != nil {
 		return "", err
 	}
 
 	tplData := struct {
-		ResourceKind       string
-		ResourceAPIVersion string
-		Group              string
-		KindSuffix         string
+		ResourceKind                string
+		ResourceAPIVersion          string
+		Group                       string
+		KindSuffix                  string
+		Ext                         string
+		NormalizedKubernetesVersion string
+		StrictSuffix                string
 	}{
 		strings.ToLower(resourceKind),
 		groupParts[len(groupParts)-1],
 		groupParts[0],
 		kindSuffix,
+		ext,
+		normalizedKubernetesVersion,
+		strictSuffix,
 	}
 
 	var buf bytes.Buffer
@@ -198,8 +280,22 @@ func writeFile(b []byte, outfile string) error {
 	return nil
 }
 
-func readCRDFromPath(specPath string) ([]byte, error) {
-	if !isURL(specPath) {
+// readCRDFromPath loads one or more raw CRD documents from specPath. Plain
+// files and http(s) URLs yield a single document; oci:// and helm:// sources
+// may bundle several CRDs and yield one document per manifest found.
+func readCRDFromPath(specPath string) ([][]byte, error) {
+	switch {
+	case isOCIRef(specPath):
+		return readCRDsFromOCI(specPath)
+	case isHelmRef(specPath):
+		return readCRDsFromHelmChart(specPath)
+	case isURL(specPath):
+		b, err := fetchURL(specPath)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{b}, nil
+	default:
 		if _, err := os.Stat(specPath); os.IsNotExist(err) {
 			return nil, fmt.Errorf("%s was not found", specPath)
 		}
@@ -209,8 +305,13 @@ func readCRDFromPath(specPath string) ([]byte, error) {
 			return nil, errors.Wrap(err, "failed to read file")
 		}
 
-		return b, nil
+		return [][]byte{b}, nil
 	}
+}
+
+// fetchURL performs a plain http(s) GET, used both for bare URL sources and
+// by the helm:// loader to download chart repo indexes and archives.
+func fetchURL(specPath string) ([]byte, error) {
 	req, err := http.NewRequest("GET", specPath, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create request")
@@ -240,3 +341,11 @@ func isURL(str string) bool {
 
 	return parsed.Scheme != ""
 }
+
+func isOCIRef(str string) bool {
+	return strings.HasPrefix(str, "oci://")
+}
+
+func isHelmRef(str string) bool {
+	return strings.HasPrefix(str, "helm://") || strings.HasSuffix(strings.ToLower(str), ".tgz")
+}
@@ -0,0 +1,283 @@
+package generator
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	extensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// goFieldMarkers holds the kubebuilder validation markers discovered on a
+// single Go struct field, keyed later by the field's `json:"..."` name.
+type goFieldMarkers struct {
+	nestedType            string
+	pattern               *string
+	minimum               *float64
+	maximum               *float64
+	minLength             *int64
+	maxLength             *int64
+	enum                  []string
+	required              bool
+	minItems              *int64
+	maxItems              *int64
+	uniqueItems           *bool
+	format                *string
+	def                   *string
+	preserveUnknownFields *bool
+}
+
+var markerLine = regexp.MustCompile(`^\+kubebuilder:validation:(\w+)(?:=(.*))?$`)
+var defaultMarkerLine = regexp.MustCompile(`^\+kubebuilder:default=(.*)$`)
+
+// parseGoSourceMarkers walks dir for Go struct declarations and extracts
+// `// +kubebuilder:validation:*` and `// +kubebuilder:default=` markers,
+// returning them indexed first by the Go type name and then by each field's
+// json name so they can be matched against CRD schema properties.
+func parseGoSourceMarkers(dir string) (map[string]map[string]*goFieldMarkers, error) {
+	types := make(map[string]map[string]*goFieldMarkers)
+
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse %s", path)
+		}
+
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				types[typeSpec.Name.Name] = parseStructFields(structType)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return types, nil
+}
+
+func parseStructFields(structType *ast.StructType) map[string]*goFieldMarkers {
+	fields := make(map[string]*goFieldMarkers)
+	for _, field := range structType.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("json")
+		jsonName := strings.Split(tag, ",")[0]
+		if jsonName == "" || jsonName == "-" {
+			continue
+		}
+
+		markers := &goFieldMarkers{nestedType: goTypeName(field.Type)}
+		for _, line := range commentLines(field) {
+			applyMarkerComment(markers, line)
+		}
+		fields[jsonName] = markers
+	}
+	return fields
+}
+
+func commentLines(field *ast.Field) []string {
+	var lines []string
+	if field.Doc != nil {
+		for _, c := range field.Doc.List {
+			lines = append(lines, strings.TrimSpace(strings.TrimPrefix(c.Text, "//")))
+		}
+	}
+	if field.Comment != nil {
+		for _, c := range field.Comment.List {
+			lines = append(lines, strings.TrimSpace(strings.TrimPrefix(c.Text, "//")))
+		}
+	}
+	return lines
+}
+
+func goTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return goTypeName(t.X)
+	case *ast.ArrayType:
+		return goTypeName(t.Elt)
+	default:
+		return ""
+	}
+}
+
+func applyMarkerComment(m *goFieldMarkers, line string) {
+	if match := markerLine.FindStringSubmatch(line); match != nil {
+		name, value := match[1], match[2]
+		switch name {
+		case "Pattern":
+			v := strings.Trim(value, `"`)
+			m.pattern = &v
+		case "Minimum":
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				m.minimum = &f
+			}
+		case "Maximum":
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				m.maximum = &f
+			}
+		case "MinLength":
+			if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+				m.minLength = &i
+			}
+		case "MaxLength":
+			if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+				m.maxLength = &i
+			}
+		case "MinItems":
+			if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+				m.minItems = &i
+			}
+		case "MaxItems":
+			if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+				m.maxItems = &i
+			}
+		case "Enum":
+			for _, v := range strings.Split(value, ";") {
+				m.enum = append(m.enum, strings.Trim(v, `"`))
+			}
+		case "Required":
+			m.required = true
+		case "UniqueItems":
+			v := value != "false"
+			m.uniqueItems = &v
+		case "Format":
+			v := strings.Trim(value, `"`)
+			m.format = &v
+		case "XPreserveUnknownFields":
+			v := value != "false"
+			m.preserveUnknownFields = &v
+		}
+		return
+	}
+
+	if match := defaultMarkerLine.FindStringSubmatch(line); match != nil {
+		v := strings.Trim(match[1], `"`)
+		m.def = &v
+	}
+}
+
+// mergeKubebuilderMarkers grafts markers parsed from Go source onto the
+// properties of schema matching kind's own fields (matched by json tag),
+// recursing into nested object schemas whose property has a known Go type.
+// Only v1 CustomResourceDefinitions are supported, since that's the shape
+// `operator-sdk generate openapi`-style Go-authored CRDs use in practice.
+func mergeKubebuilderMarkers(schema *extensionsv1.JSONSchemaProps, kind string, types map[string]map[string]*goFieldMarkers) {
+	fields, ok := types[kind]
+	if !ok || schema == nil || schema.Properties == nil {
+		return
+	}
+
+	for name, markers := range fields {
+		prop, ok := schema.Properties[name]
+		if !ok {
+			continue
+		}
+
+		if markers.pattern != nil {
+			prop.Pattern = *markers.pattern
+		}
+		if markers.minimum != nil {
+			prop.Minimum = markers.minimum
+		}
+		if markers.maximum != nil {
+			prop.Maximum = markers.maximum
+		}
+		if markers.minLength != nil {
+			prop.MinLength = markers.minLength
+		}
+		if markers.maxLength != nil {
+			prop.MaxLength = markers.maxLength
+		}
+		if markers.minItems != nil {
+			prop.MinItems = markers.minItems
+		}
+		if markers.maxItems != nil {
+			prop.MaxItems = markers.maxItems
+		}
+		if markers.uniqueItems != nil {
+			prop.UniqueItems = *markers.uniqueItems
+		}
+		if markers.format != nil {
+			prop.Format = *markers.format
+		}
+		if markers.preserveUnknownFields != nil {
+			prop.XPreserveUnknownFields = markers.preserveUnknownFields
+		}
+		if len(markers.enum) > 0 {
+			prop.Enum = make([]extensionsv1.JSON, 0, len(markers.enum))
+			for _, v := range markers.enum {
+				prop.Enum = append(prop.Enum, extensionsv1.JSON{Raw: marshalMarkerValue(v)})
+			}
+		}
+		if markers.def != nil {
+			prop.Default = &extensionsv1.JSON{Raw: marshalMarkerValue(*markers.def)}
+		}
+		if markers.required && !containsString(schema.Required, name) {
+			schema.Required = append(schema.Required, name)
+		}
+
+		if markers.nestedType != "" {
+			mergeKubebuilderMarkers(&prop, markers.nestedType, types)
+		}
+
+		schema.Properties[name] = prop
+	}
+}
+
+func containsString(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
+
+// marshalMarkerValue turns a raw marker value into its JSON representation,
+// preferring a number or bool literal and falling back to a quoted string.
+func marshalMarkerValue(v string) []byte {
+	if b, err := json.Marshal(json.Number(v)); err == nil {
+		var f float64
+		if json.Unmarshal(b, &f) == nil {
+			return b
+		}
+	}
+	if v == "true" || v == "false" {
+		return []byte(v)
+	}
+	b, _ := json.Marshal(v)
+	return b
+}
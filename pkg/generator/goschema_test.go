@@ -0,0 +1,129 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	extensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func writeGoSource(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func TestParseGoSourceMarkers(t *testing.T) {
+	dir := t.TempDir()
+	writeGoSource(t, dir, "widget_types.go", `package types
+
+type WidgetSpec struct {
+	// +kubebuilder:validation:Pattern="^[a-z]+$"
+	// +kubebuilder:validation:Required
+	Name string `+"`json:\"name\"`"+`
+
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	Size int `+"`json:\"size\"`"+`
+}
+`)
+
+	types, err := parseGoSourceMarkers(dir)
+	if err != nil {
+		t.Fatalf("parseGoSourceMarkers returned error: %v", err)
+	}
+
+	fields, ok := types["WidgetSpec"]
+	if !ok {
+		t.Fatalf("expected WidgetSpec to be discovered, got %v", types)
+	}
+
+	name, ok := fields["name"]
+	if !ok {
+		t.Fatalf("expected name field, got %v", fields)
+	}
+	if name.pattern == nil || *name.pattern != "^[a-z]+$" {
+		t.Errorf("expected pattern ^[a-z]+$, got %v", name.pattern)
+	}
+	if !name.required {
+		t.Errorf("expected name to be required")
+	}
+
+	size, ok := fields["size"]
+	if !ok {
+		t.Fatalf("expected size field, got %v", fields)
+	}
+	if size.minimum == nil || *size.minimum != 1 {
+		t.Errorf("expected minimum 1, got %v", size.minimum)
+	}
+	if size.maximum == nil || *size.maximum != 10 {
+		t.Errorf("expected maximum 10, got %v", size.maximum)
+	}
+}
+
+func TestMergeKubebuilderMarkersDoesNotDuplicateRequired(t *testing.T) {
+	schema := &extensionsv1.JSONSchemaProps{
+		Required: []string{"name"},
+		Properties: map[string]extensionsv1.JSONSchemaProps{
+			"name": {Type: "string"},
+		},
+	}
+
+	required := true
+	types := map[string]map[string]*goFieldMarkers{
+		"Widget": {
+			"name": {required: required},
+		},
+	}
+
+	mergeKubebuilderMarkers(schema, "Widget", types)
+
+	count := 0
+	for _, r := range schema.Required {
+		if r == "name" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected \"name\" to appear once in Required, got %v", schema.Required)
+	}
+}
+
+func TestMergeKubebuilderMarkersAppliesPattern(t *testing.T) {
+	schema := &extensionsv1.JSONSchemaProps{
+		Properties: map[string]extensionsv1.JSONSchemaProps{
+			"name": {Type: "string"},
+		},
+	}
+
+	pattern := "^[a-z]+$"
+	types := map[string]map[string]*goFieldMarkers{
+		"Widget": {
+			"name": {pattern: &pattern},
+		},
+	}
+
+	mergeKubebuilderMarkers(schema, "Widget", types)
+
+	if schema.Properties["name"].Pattern != pattern {
+		t.Errorf("expected pattern %q, got %q", pattern, schema.Properties["name"].Pattern)
+	}
+}
+
+func TestMarshalMarkerValue(t *testing.T) {
+	cases := map[string]string{
+		"1":     "1",
+		"true":  "true",
+		"false": "false",
+		"hello": `"hello"`,
+		"1.5":   "1.5",
+	}
+	for in, want := range cases {
+		got := string(marshalMarkerValue(in))
+		if got != want {
+			t.Errorf("marshalMarkerValue(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
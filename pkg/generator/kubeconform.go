@@ -0,0 +1,188 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	extensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+const kubeconformCrdsPattern = "{{ .NormalizedKubernetesVersion }}-standalone{{ .StrictSuffix }}/{{ .ResourceKind }}{{ .KindSuffix }}.{{ .Ext }}"
+
+// GVK identifies the CRD version a generated kubeconform schema came from.
+type GVK struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// IndexEntry is one row of the index.json emitted alongside a kubeconform
+// schema tree, letting downstream tooling discover schemas without walking
+// the directory tree.
+type IndexEntry struct {
+	File string `json:"file"`
+	GVK  GVK    `json:"gvk"`
+}
+
+// GenerateKubeconformTree expands paths like GenerateAll, but lays the
+// resulting schemas out the way kubeconform's -schema-location template
+// expects ({{ .NormalizedKubernetesVersion }}-standalone{{ .StrictSuffix }}/
+// {{ .ResourceKind }}{{ .KindSuffix }}.json). In strict mode it additionally
+// emits a "-strict" variant of every schema with additionalProperties: false
+// injected at every object node, side-by-side with the non-strict one. An
+// index.json listing every file with its GVK is included in the result.
+func GenerateKubeconformTree(paths []string, goSourceDir, format, kubernetesVersion string, strict bool) (schemas map[string]string, err error) {
+	ser, err := NewSerializer(format)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded, err := expandPaths(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	var goTypes map[string]map[string]*goFieldMarkers
+	if goSourceDir != "" {
+		goTypes, err = parseGoSourceMarkers(goSourceDir)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse go source markers")
+		}
+	}
+
+	normalizedVersion := normalizeKubernetesVersion(kubernetesVersion)
+
+	schemas = make(map[string]string)
+	var index []IndexEntry
+	msg := ""
+
+	for _, p := range expanded {
+		crdBlobs, err := readCRDFromPath(p)
+		if err != nil {
+			msg += fmt.Sprintf("\t%v: %v\n", p, err)
+			continue
+		}
+
+		for _, blob := range crdBlobs {
+			docs, err := splitYAMLDocuments(blob)
+			if err != nil {
+				msg += fmt.Sprintf("\t%v: %v\n", p, err)
+				continue
+			}
+
+			for _, doc := range docs {
+				crdObj, err := decodeCRD(doc)
+				if err != nil {
+					msg += fmt.Sprintf("\t%v: %v\n", p, err)
+					continue
+				}
+
+				crd, ok := crdObj.(*extensionsv1.CustomResourceDefinition)
+				if !ok {
+					msg += fmt.Sprintf("\t%v: kubeconform schema trees are only supported for v1 CustomResourceDefinitions\n", p)
+					continue
+				}
+
+				wrote := false
+				for _, version := range crd.Spec.Versions {
+					if version.Schema == nil {
+						continue
+					}
+
+					schema := *version.Schema.OpenAPIV3Schema
+					if goTypes != nil {
+						mergeKubebuilderMarkers(&schema, crd.Spec.Names.Kind, goTypes)
+					}
+
+					gvk := GVK{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind}
+					resourceAPIVersion := fmt.Sprintf("%v/%v", crd.Spec.Group, version.Name)
+
+					if err := addKubeconformSchema(schemas, &index, schema, crd.Spec.Names.Kind, resourceAPIVersion, normalizedVersion, false, ser, gvk); err != nil {
+						return nil, err
+					}
+					wrote = true
+
+					if strict {
+						strictSchema := schema.DeepCopy()
+						injectAdditionalPropertiesFalse(strictSchema)
+						if err := addKubeconformSchema(schemas, &index, *strictSchema, crd.Spec.Names.Kind, resourceAPIVersion, normalizedVersion, true, ser, gvk); err != nil {
+							return nil, err
+						}
+					}
+				}
+				if !wrote {
+					msg += fmt.Sprintf("\t%v: No validation specified\n", p)
+				}
+			}
+		}
+	}
+
+	if msg != "" {
+		return schemas, fmt.Errorf("Failed to generate schema for following sources:\n%v", msg)
+	}
+
+	indexBytes, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return schemas, errors.Wrap(err, "failed to marshal index")
+	}
+	schemas["index.json"] = string(indexBytes)
+
+	return schemas, nil
+}
+
+func addKubeconformSchema(schemas map[string]string, index *[]IndexEntry, schema extensionsv1.JSONSchemaProps, resourceKind, resourceAPIVersion, normalizedVersion string, strict bool, ser Serializer, gvk GVK) error {
+	name, err := generateFilename(kubeconformCrdsPattern, resourceKind, resourceAPIVersion, ser.Ext(), normalizedVersion, strict)
+	if err != nil {
+		return err
+	}
+
+	b, err := ser.Serialize(schema)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal schema")
+	}
+
+	schemas[name] = string(b)
+	*index = append(*index, IndexEntry{File: name, GVK: gvk})
+	return nil
+}
+
+// normalizeKubernetesVersion mirrors kubeconform's own "master-standalone"
+// fallback for schemas that aren't tied to a specific Kubernetes release,
+// which is what CRDs are: they aren't versioned alongside the cluster.
+func normalizeKubernetesVersion(kubernetesVersion string) string {
+	if kubernetesVersion == "" {
+		return "master"
+	}
+	return strings.ToLower(kubernetesVersion)
+}
+
+// injectAdditionalPropertiesFalse recursively sets additionalProperties:
+// false on every object node (one with declared properties) of schema that
+// doesn't already specify additionalProperties, for --strict mode.
+func injectAdditionalPropertiesFalse(schema *extensionsv1.JSONSchemaProps) {
+	if schema == nil {
+		return
+	}
+
+	if len(schema.Properties) > 0 && schema.AdditionalProperties == nil {
+		schema.AdditionalProperties = &extensionsv1.JSONSchemaPropsOrBool{Allows: false}
+	}
+
+	for name, prop := range schema.Properties {
+		injectAdditionalPropertiesFalse(&prop)
+		schema.Properties[name] = prop
+	}
+
+	if schema.Items != nil {
+		injectAdditionalPropertiesFalse(schema.Items.Schema)
+		for i := range schema.Items.JSONSchemas {
+			injectAdditionalPropertiesFalse(&schema.Items.JSONSchemas[i])
+		}
+	}
+
+	if schema.AdditionalProperties != nil {
+		injectAdditionalPropertiesFalse(schema.AdditionalProperties.Schema)
+	}
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"testing"
+
+	extensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestInjectAdditionalPropertiesFalse(t *testing.T) {
+	schema := &extensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]extensionsv1.JSONSchemaProps{
+			"spec": {
+				Type: "object",
+				Properties: map[string]extensionsv1.JSONSchemaProps{
+					"name": {Type: "string"},
+				},
+			},
+		},
+	}
+
+	injectAdditionalPropertiesFalse(schema)
+
+	if schema.AdditionalProperties == nil || schema.AdditionalProperties.Allows {
+		t.Fatalf("expected root additionalProperties: false, got %v", schema.AdditionalProperties)
+	}
+
+	spec := schema.Properties["spec"]
+	if spec.AdditionalProperties == nil || spec.AdditionalProperties.Allows {
+		t.Fatalf("expected nested spec additionalProperties: false, got %v", spec.AdditionalProperties)
+	}
+
+	name := spec.Properties["name"]
+	if name.AdditionalProperties != nil {
+		t.Errorf("expected no additionalProperties on a leaf scalar, got %v", name.AdditionalProperties)
+	}
+}
+
+func TestInjectAdditionalPropertiesFalseRespectsExisting(t *testing.T) {
+	schema := &extensionsv1.JSONSchemaProps{
+		Type:                 "object",
+		Properties:           map[string]extensionsv1.JSONSchemaProps{"name": {Type: "string"}},
+		AdditionalProperties: &extensionsv1.JSONSchemaPropsOrBool{Allows: true},
+	}
+
+	injectAdditionalPropertiesFalse(schema)
+
+	if !schema.AdditionalProperties.Allows {
+		t.Errorf("expected an existing additionalProperties setting to be left untouched")
+	}
+}
+
+func TestGenerateFilenameKubeconformPattern(t *testing.T) {
+	name, err := generateFilename(kubeconformCrdsPattern, "Widget", "example.com/v1", "json", "master", false)
+	if err != nil {
+		t.Fatalf("generateFilename returned error: %v", err)
+	}
+	want := "master-standalone/widget-example-v1.json"
+	if name != want {
+		t.Errorf("generateFilename() = %q, want %q", name, want)
+	}
+}
+
+func TestGenerateFilenameKubeconformStrict(t *testing.T) {
+	name, err := generateFilename(kubeconformCrdsPattern, "Widget", "example.com/v1", "json", "master", true)
+	if err != nil {
+		t.Fatalf("generateFilename returned error: %v", err)
+	}
+	want := "master-standalone-strict/widget-example-v1.json"
+	if name != want {
+		t.Errorf("generateFilename() = %q, want %q", name, want)
+	}
+}
+
+func TestNormalizeKubernetesVersion(t *testing.T) {
+	cases := map[string]string{
+		"":        "master",
+		"V1.28.0": "v1.28.0",
+		"v1.28.0": "v1.28.0",
+	}
+	for in, want := range cases {
+		if got := normalizeKubernetesVersion(in); got != want {
+			t.Errorf("normalizeKubernetesVersion(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
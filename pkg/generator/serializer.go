@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Serializer renders a decoded OpenAPIv3 schema to its final on-disk
+// representation and reports the file extension that representation should
+// be written with.
+type Serializer interface {
+	Serialize(schema interface{}) ([]byte, error)
+	Ext() string
+}
+
+// NewSerializer returns the Serializer for the given --format value. An
+// empty format keeps the tool's historical behaviour of pretty-printed JSON.
+func NewSerializer(format string) (Serializer, error) {
+	switch format {
+	case "", "json-pretty":
+		return jsonPrettySerializer{}, nil
+	case "json":
+		return jsonSerializer{}, nil
+	case "yaml":
+		return yamlSerializer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q, must be one of: json, json-pretty, yaml", format)
+	}
+}
+
+type jsonSerializer struct{}
+
+func (jsonSerializer) Serialize(schema interface{}) ([]byte, error) {
+	return json.Marshal(schema)
+}
+
+func (jsonSerializer) Ext() string { return "json" }
+
+type jsonPrettySerializer struct{}
+
+func (jsonPrettySerializer) Serialize(schema interface{}) ([]byte, error) {
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func (jsonPrettySerializer) Ext() string { return "json" }
+
+// yamlSerializer converts through JSON first, analogous to meshkit's
+// encoding.ToYaml helper, since the decoded schema types only carry json tags.
+type yamlSerializer struct{}
+
+func (yamlSerializer) Serialize(schema interface{}) ([]byte, error) {
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.JSONToYAML(b)
+}
+
+func (yamlSerializer) Ext() string { return "yaml" }
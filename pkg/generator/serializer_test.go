@@ -0,0 +1,57 @@
+package generator
+
+import "testing"
+
+func TestNewSerializerExt(t *testing.T) {
+	cases := map[string]string{
+		"":            "json",
+		"json-pretty": "json",
+		"json":        "json",
+		"yaml":        "yaml",
+	}
+	for format, wantExt := range cases {
+		ser, err := NewSerializer(format)
+		if err != nil {
+			t.Fatalf("NewSerializer(%q) returned error: %v", format, err)
+		}
+		if ser.Ext() != wantExt {
+			t.Errorf("NewSerializer(%q).Ext() = %q, want %q", format, ser.Ext(), wantExt)
+		}
+	}
+}
+
+func TestNewSerializerUnknownFormat(t *testing.T) {
+	if _, err := NewSerializer("toml"); err == nil {
+		t.Fatal("expected an error for an unknown format, got nil")
+	}
+}
+
+func TestYamlSerializerSerialize(t *testing.T) {
+	ser := yamlSerializer{}
+	schema := map[string]interface{}{"type": "string"}
+
+	b, err := ser.Serialize(schema)
+	if err != nil {
+		t.Fatalf("Serialize returned error: %v", err)
+	}
+
+	want := "type: string\n"
+	if string(b) != want {
+		t.Errorf("Serialize() = %q, want %q", string(b), want)
+	}
+}
+
+func TestJsonPrettySerializerSerialize(t *testing.T) {
+	ser := jsonPrettySerializer{}
+	schema := map[string]interface{}{"type": "string"}
+
+	b, err := ser.Serialize(schema)
+	if err != nil {
+		t.Fatalf("Serialize returned error: %v", err)
+	}
+
+	want := "{\n  \"type\": \"string\"\n}"
+	if string(b) != want {
+		t.Errorf("Serialize() = %q, want %q", string(b), want)
+	}
+}
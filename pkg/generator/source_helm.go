@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/repo"
+	"sigs.k8s.io/yaml"
+)
+
+// readCRDsFromHelmChart loads a Helm chart - a local .tgz archive, an
+// unpacked chart directory, or a "helm://repo-host/path/chart[@version]"
+// reference resolved against the repo's index.yaml - and walks its crds/
+// directory for embedded CustomResourceDefinition manifests.
+func readCRDsFromHelmChart(chartRef string) ([][]byte, error) {
+	var (
+		archive []byte
+		err     error
+	)
+
+	if strings.HasPrefix(chartRef, "helm://") {
+		archive, err = fetchHelmChartArchive(strings.TrimPrefix(chartRef, "helm://"))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		archive, err = ioutil.ReadFile(chartRef)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read helm chart archive")
+		}
+	}
+
+	chrt, err := loader.LoadArchive(bytes.NewReader(archive))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load helm chart")
+	}
+
+	var docs [][]byte
+	for _, crd := range chrt.CRDObjects() {
+		docs = append(docs, crd.File.Data)
+	}
+
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no CRDs found in crds/ directory of chart %s", chartRef)
+	}
+	return docs, nil
+}
+
+// fetchHelmChartArchive resolves "repo-host/path/chart[@version]" against the
+// chart repository's index.yaml - the same protocol `helm repo add` and
+// `helm pull` use - and downloads the matching .tgz archive.
+func fetchHelmChartArchive(ref string) ([]byte, error) {
+	chartRef, version, hasVersion := strings.Cut(ref, "@")
+
+	i := strings.LastIndex(chartRef, "/")
+	if i == -1 {
+		return nil, fmt.Errorf("helm chart reference %q must be of the form repo-host/path/chart[@version]", ref)
+	}
+	repoURL, chartName := "https://"+chartRef[:i], chartRef[i+1:]
+
+	indexBytes, err := fetchURL(repoURL + "/index.yaml")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch helm repo index")
+	}
+
+	var index repo.IndexFile
+	if err := yaml.Unmarshal(indexBytes, &index); err != nil {
+		return nil, errors.Wrap(err, "failed to parse helm repo index")
+	}
+	index.SortEntries()
+
+	cv, err := index.Get(chartName, "")
+	if hasVersion {
+		cv, err = index.Get(chartName, version)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve helm chart version")
+	}
+	if len(cv.URLs) == 0 {
+		return nil, fmt.Errorf("chart %q has no download URLs in repo index", chartName)
+	}
+
+	chartURL := cv.URLs[0]
+	if !isURL(chartURL) {
+		chartURL = repoURL + "/" + chartURL
+	}
+
+	return fetchURL(chartURL)
+}
@@ -0,0 +1,24 @@
+package generator
+
+import "testing"
+
+func TestIsHelmRef(t *testing.T) {
+	cases := map[string]bool{
+		"helm://charts.example.com/stable/widget": true,
+		"./local-chart.tgz":                       true,
+		"./LOCAL-CHART.TGZ":                       true,
+		"oci://ghcr.io/example/crds:v1":           false,
+		"./crd.yaml":                              false,
+	}
+	for in, want := range cases {
+		if got := isHelmRef(in); got != want {
+			t.Errorf("isHelmRef(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestFetchHelmChartArchiveRejectsRefWithoutSlash(t *testing.T) {
+	if _, err := fetchHelmChartArchive("widget"); err == nil {
+		t.Fatal("expected an error for a ref with no repo-host/chart separator, got nil")
+	}
+}
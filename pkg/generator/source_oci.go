@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+)
+
+// readCRDsFromOCI pulls the OCI artifact referenced by ociRef (e.g.
+// "oci://ghcr.io/example/crds:v1") and extracts every CustomResourceDefinition
+// manifest found in its layers. This follows the same convention tools like
+// hauler and ocil use to distribute Kubernetes content as plain tar layers.
+func readCRDsFromOCI(ociRef string) ([][]byte, error) {
+	ref, err := name.ParseReference(strings.TrimPrefix(ociRef, "oci://"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse oci reference")
+	}
+
+	img, err := remote.Image(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to pull oci artifact")
+	}
+
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read oci layers")
+	}
+
+	var docs [][]byte
+	for _, layer := range layers {
+		rc, err := layer.Uncompressed()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read oci layer")
+		}
+		layerDocs, err := extractCRDsFromTar(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, layerDocs...)
+	}
+
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no CustomResourceDefinition manifests found in %s", ociRef)
+	}
+	return docs, nil
+}
+
+func extractCRDsFromTar(r io.Reader) ([][]byte, error) {
+	var docs [][]byte
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read oci layer tar entry")
+		}
+		if hdr.Typeflag != tar.TypeReg || !isManifestFile(hdr.Name) {
+			continue
+		}
+
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read oci layer tar entry contents")
+		}
+		if looksLikeCRDManifest(b) {
+			docs = append(docs, b)
+		}
+	}
+	return docs, nil
+}
+
+func isManifestFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
+func looksLikeCRDManifest(b []byte) bool {
+	return bytes.Contains(b, []byte("kind: CustomResourceDefinition")) ||
+		bytes.Contains(b, []byte(`"kind": "CustomResourceDefinition"`)) ||
+		bytes.Contains(b, []byte(`"kind":"CustomResourceDefinition"`))
+}
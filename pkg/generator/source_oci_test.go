@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"archive/tar"
+	"bytes"
+	"testing"
+)
+
+func TestIsOCIRef(t *testing.T) {
+	cases := map[string]bool{
+		"oci://ghcr.io/example/crds:v1": true,
+		"ghcr.io/example/crds:v1":       false,
+		"./local-chart.tgz":             false,
+	}
+	for in, want := range cases {
+		if got := isOCIRef(in); got != want {
+			t.Errorf("isOCIRef(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestIsManifestFile(t *testing.T) {
+	cases := map[string]bool{
+		"crds/widget.yaml": true,
+		"crds/widget.yml":  true,
+		"crds/widget.json": true,
+		"crds/widget.JSON": true,
+		"README.md":        false,
+		"crds/widget.tar":  false,
+	}
+	for in, want := range cases {
+		if got := isManifestFile(in); got != want {
+			t.Errorf("isManifestFile(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestLooksLikeCRDManifest(t *testing.T) {
+	cases := map[string]bool{
+		"kind: CustomResourceDefinition\nmetadata:\n  name: widgets.example.com\n": true,
+		`{"kind": "CustomResourceDefinition"}`:                                     true,
+		`{"kind":"CustomResourceDefinition"}`:                                      true,
+		"apiVersion: apps/v1\nkind: Deployment\n":                                  false,
+	}
+	for in, want := range cases {
+		if got := looksLikeCRDManifest([]byte(in)); got != want {
+			t.Errorf("looksLikeCRDManifest(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func writeTarEntry(t *testing.T, tw *tar.Writer, name string, content string) {
+	t.Helper()
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}); err != nil {
+		t.Fatalf("failed to write tar header for %s: %v", name, err)
+	}
+	if _, err := tw.Write([]byte(content)); err != nil {
+		t.Fatalf("failed to write tar content for %s: %v", name, err)
+	}
+}
+
+func TestExtractCRDsFromTar(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	writeTarEntry(t, tw, "crds/widget.yaml", "kind: CustomResourceDefinition\n")
+	writeTarEntry(t, tw, "templates/deployment.yaml", "kind: Deployment\n")
+	writeTarEntry(t, tw, "README.md", "kind: CustomResourceDefinition\n")
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar writer: %v", err)
+	}
+
+	docs, err := extractCRDsFromTar(&buf)
+	if err != nil {
+		t.Fatalf("extractCRDsFromTar returned error: %v", err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("expected 1 CRD document, got %d: %v", len(docs), docs)
+	}
+	if string(docs[0]) != "kind: CustomResourceDefinition\n" {
+		t.Errorf("docs[0] = %q, want %q", docs[0], "kind: CustomResourceDefinition\n")
+	}
+}
+
+func TestExtractCRDsFromTarNoMatches(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	writeTarEntry(t, tw, "templates/deployment.yaml", "kind: Deployment\n")
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar writer: %v", err)
+	}
+
+	docs, err := extractCRDsFromTar(&buf)
+	if err != nil {
+		t.Fatalf("extractCRDsFromTar returned error: %v", err)
+	}
+	if len(docs) != 0 {
+		t.Errorf("expected no documents, got %v", docs)
+	}
+}
@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/pkg/errors"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// expandPaths resolves each of paths to the concrete list of sources
+// readCRDFromPath should load: oci://, helm:// and http(s) references pass
+// through untouched, directories are walked recursively for manifest files,
+// and glob patterns (including "**") are expanded in place.
+func expandPaths(paths []string) ([]string, error) {
+	var out []string
+	for _, p := range paths {
+		if isOCIRef(p) || isHelmRef(p) || isURL(p) {
+			out = append(out, p)
+			continue
+		}
+
+		if strings.ContainsAny(p, "*?[") {
+			matches, err := doublestar.FilepathGlob(p)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to expand glob %q", p)
+			}
+			out = append(out, matches...)
+			continue
+		}
+
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to stat %q", p)
+		}
+		if !info.IsDir() {
+			out = append(out, p)
+			continue
+		}
+
+		err = filepath.WalkDir(p, func(walked string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !isManifestFile(walked) {
+				return nil
+			}
+			out = append(out, walked)
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to walk %q", p)
+		}
+	}
+	return out, nil
+}
+
+// splitYAMLDocuments splits a (possibly single-document) "---"-separated
+// YAML stream into its individual documents, discarding any that are empty.
+func splitYAMLDocuments(raw []byte) ([][]byte, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(raw)))
+
+	var docs [][]byte
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to split multi-document YAML stream")
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
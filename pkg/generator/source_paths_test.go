@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestSplitYAMLDocuments(t *testing.T) {
+	raw := []byte("a: 1\n---\nb: 2\n---\n\n")
+
+	docs, err := splitYAMLDocuments(raw)
+	if err != nil {
+		t.Fatalf("splitYAMLDocuments returned error: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("expected 2 documents, got %d: %v", len(docs), docs)
+	}
+	if string(docs[0]) != "a: 1\n" {
+		t.Errorf("doc[0] = %q, want %q", docs[0], "a: 1\n")
+	}
+	if string(docs[1]) != "b: 2\n" {
+		t.Errorf("doc[1] = %q, want %q", docs[1], "b: 2\n")
+	}
+}
+
+func TestSplitYAMLDocumentsSingleDoc(t *testing.T) {
+	docs, err := splitYAMLDocuments([]byte("a: 1\n"))
+	if err != nil {
+		t.Fatalf("splitYAMLDocuments returned error: %v", err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("expected 1 document, got %d: %v", len(docs), docs)
+	}
+}
+
+func TestExpandPathsPassesThroughNonFileRefs(t *testing.T) {
+	paths := []string{"oci://example.com/chart:latest", "helm://example.com/chart", "https://example.com/crd.yaml"}
+
+	out, err := expandPaths(paths)
+	if err != nil {
+		t.Fatalf("expandPaths returned error: %v", err)
+	}
+	if len(out) != len(paths) {
+		t.Fatalf("expected %d entries, got %d: %v", len(paths), len(out), out)
+	}
+	for i, p := range paths {
+		if out[i] != p {
+			t.Errorf("out[%d] = %q, want %q", i, out[i], p)
+		}
+	}
+}
+
+func TestExpandPathsWalksDirectories(t *testing.T) {
+	dir := t.TempDir()
+	crdFile := filepath.Join(dir, "crd.yaml")
+	if err := os.WriteFile(crdFile, []byte("kind: CustomResourceDefinition\n"), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a manifest"), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	out, err := expandPaths([]string{dir})
+	if err != nil {
+		t.Fatalf("expandPaths returned error: %v", err)
+	}
+	if len(out) != 1 || out[0] != crdFile {
+		t.Errorf("expandPaths(%q) = %v, want [%q]", dir, out, crdFile)
+	}
+}
+
+func TestExpandPathsGlob(t *testing.T) {
+	dir := t.TempDir()
+	var want []string
+	for _, name := range []string{"a.yaml", "b.yaml"} {
+		f := filepath.Join(dir, name)
+		if err := os.WriteFile(f, []byte("kind: CustomResourceDefinition\n"), 0644); err != nil {
+			t.Fatalf("failed to write fixture: %v", err)
+		}
+		want = append(want, f)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "c.txt"), []byte("skip me"), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	out, err := expandPaths([]string{filepath.Join(dir, "*.yaml")})
+	if err != nil {
+		t.Fatalf("expandPaths returned error: %v", err)
+	}
+	sort.Strings(out)
+	sort.Strings(want)
+	if len(out) != len(want) {
+		t.Fatalf("expandPaths glob = %v, want %v", out, want)
+	}
+	for i := range want {
+		if out[i] != want[i] {
+			t.Errorf("out[%d] = %q, want %q", i, out[i], want[i])
+		}
+	}
+}